@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+)
+
+var errPartialPartitions = errors.New("partition must be set on every message in the batch, or omitted on all of them")
+
+// BatchMessage is one record in a POST /publish/{topic}/batch request body.
+type BatchMessage struct {
+	Key       string            `json:"key"`
+	Partition *int              `json:"partition"`
+	Headers   map[string]string `json:"headers"`
+	Timestamp *time.Time        `json:"timestamp"`
+	Value     json.RawMessage   `json:"value"`
+}
+
+// DeliveryReport is the per-message outcome returned by the batch endpoint,
+// so a client knows exactly which records in the batch failed.
+type DeliveryReport struct {
+	Index     int    `json:"index"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Error     string `json:"error,omitempty"`
+}
+
+// batchWriterData is stashed on each message's WriterData when publishing a
+// batch. A pooled writer's Completion callback (dispatchCompletion) is
+// shared by every request that happens to hit that writer, so each message
+// needs to carry its own route back to the batch request that sent it.
+type batchWriterData struct {
+	tracker *batchTracker
+	index   int
+}
+
+// batchTracker collects delivery reports for one in-flight batch publish
+// request as dispatchCompletion reports them, possibly from multiple
+// goroutines if the pooled writer splits the batch across internal writes.
+type batchTracker struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	reports []DeliveryReport
+}
+
+func newBatchTracker(n int) *batchTracker {
+	t := &batchTracker{reports: make([]DeliveryReport, n)}
+	t.wg.Add(n)
+	return t
+}
+
+// record stores m's delivery outcome and marks it done. completionErr is
+// nil on success.
+func (t *batchTracker) record(m kafka.Message, completionErr error) {
+	wd := m.WriterData.(batchWriterData)
+
+	t.mu.Lock()
+	t.reports[wd.index] = newDeliveryReport(wd.index, m, completionErr)
+	t.mu.Unlock()
+
+	t.wg.Done()
+}
+
+// dispatchCompletion is installed as every pooled writer's Completion
+// callback. A plain single-message publish through writerPool.Get leaves
+// WriterData unset and is ignored here - writer.WriteMessages already
+// reports that outcome synchronously. A batch publish stashes a
+// batchWriterData on every message so this one callback can route each
+// completed message back to the tracker for the request that sent it.
+func dispatchCompletion(messages []kafka.Message, err error) {
+	for _, m := range messages {
+		if wd, ok := m.WriterData.(batchWriterData); ok {
+			wd.tracker.record(m, err)
+		}
+	}
+}
+
+func batchPublishHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	topic := params["topic"]
+	cluster := r.URL.Query().Get("cluster")
+
+	var batch []BatchMessage
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		log.WithField("error", err).Error("Failed to decode batch request body")
+		handleBadRequest(w)
+		return
+	}
+
+	if len(batch) == 0 {
+		log.Error("Empty batch")
+		handleBadRequest(w)
+		return
+	}
+
+	explicitPartitions, err := hasExplicitPartitions(batch)
+	if err != nil {
+		log.WithField("error", err).Error("Invalid per-message partitions")
+		handleBadRequest(w)
+		return
+	}
+
+	var writer *kafka.Writer
+	if explicitPartitions {
+		writer, err = writerPool.GetDirect(cluster, topic)
+	} else {
+		writer, err = writerPool.Get(cluster, topic)
+	}
+	if err != nil {
+		log.WithField("error", err).Error("Failed to get writer")
+		handleBadRequest(w)
+		return
+	}
+
+	tracker := newBatchTracker(len(batch))
+
+	msgs := make([]kafka.Message, len(batch))
+	for i, bm := range batch {
+		msg := kafka.Message{
+			Key:        []byte(bm.Key),
+			Value:      []byte(bm.Value),
+			WriterData: batchWriterData{tracker: tracker, index: i},
+		}
+		if bm.Partition != nil {
+			msg.Partition = *bm.Partition
+		}
+		if bm.Timestamp != nil {
+			msg.Time = *bm.Timestamp
+		}
+		for k, v := range bm.Headers {
+			msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+		msgs[i] = msg
+	}
+
+	ctx, span := tracer.Start(r.Context(), "kafka.produce_batch")
+	defer span.End()
+
+	if err := writer.WriteMessages(ctx, msgs...); err != nil {
+		log.WithField("error", err).Error("Batch write returned an error")
+	}
+	tracker.wg.Wait()
+
+	failures := 0
+	for _, report := range tracker.reports {
+		if report.Error != "" {
+			failures++
+			continue
+		}
+		messagesProduced.WithLabelValues(topic).Inc()
+	}
+
+	if failures > 0 {
+		log.WithField("failures", failures).WithField("total", len(msgs)).Error("Batch publish had failures")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case failures == len(msgs):
+		w.WriteHeader(http.StatusInternalServerError)
+	case failures > 0:
+		w.WriteHeader(http.StatusMultiStatus)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(tracker.reports)
+}
+
+// newDeliveryReport builds the per-message outcome for a batch message
+// handed back by dispatchCompletion.
+func newDeliveryReport(index int, m kafka.Message, completionErr error) DeliveryReport {
+	report := DeliveryReport{
+		Index:     index,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+	}
+	if completionErr != nil {
+		report.Error = completionErr.Error()
+	}
+	return report
+}
+
+// hasExplicitPartitions reports whether the batch asks to route messages to
+// specific partitions. kafka-go only honors kafka.Message.Partition when the
+// writer has no Balancer, so a batch must either set it on every message or
+// none of them - a partial mix would silently balancer-route the rest.
+func hasExplicitPartitions(batch []BatchMessage) (bool, error) {
+	explicit := 0
+	for _, bm := range batch {
+		if bm.Partition != nil {
+			explicit++
+		}
+	}
+	if explicit == 0 {
+		return false, nil
+	}
+	if explicit != len(batch) {
+		return false, errPartialPartitions
+	}
+	return true, nil
+}
+
+func publishStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(writerPool.Stats())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestNewDeliveryReportSuccess(t *testing.T) {
+	msg := kafka.Message{Partition: 3, Offset: 42}
+
+	report := newDeliveryReport(2, msg, nil)
+
+	if report.Index != 2 || report.Partition != 3 || report.Offset != 42 {
+		t.Fatalf("got %+v, want Index=2 Partition=3 Offset=42", report)
+	}
+	if report.Error != "" {
+		t.Fatalf("Error = %q, want empty on success", report.Error)
+	}
+}
+
+func TestNewDeliveryReportFailure(t *testing.T) {
+	report := newDeliveryReport(0, kafka.Message{}, errors.New("leader not available"))
+
+	if report.Error == "" {
+		t.Fatal("Error = \"\", want the completion error's message")
+	}
+}
+
+func TestBatchTrackerRecordRoutesByWriterData(t *testing.T) {
+	tracker := newBatchTracker(2)
+
+	tracker.record(kafka.Message{WriterData: batchWriterData{tracker: tracker, index: 1}, Offset: 10}, nil)
+	tracker.record(kafka.Message{WriterData: batchWriterData{tracker: tracker, index: 0}, Offset: 5}, errors.New("boom"))
+
+	tracker.wg.Wait()
+
+	if tracker.reports[0].Offset != 5 || tracker.reports[0].Error == "" {
+		t.Errorf("reports[0] = %+v, want the failed write at offset 5", tracker.reports[0])
+	}
+	if tracker.reports[1].Offset != 10 || tracker.reports[1].Error != "" {
+		t.Errorf("reports[1] = %+v, want the successful write at offset 10", tracker.reports[1])
+	}
+}
+
+func TestDispatchCompletionIgnoresNonBatchMessages(t *testing.T) {
+	// A plain publish through writerPool.Get leaves WriterData unset;
+	// dispatchCompletion must not panic trying to route it.
+	dispatchCompletion([]kafka.Message{{}}, nil)
+}
+
+func TestHasExplicitPartitions(t *testing.T) {
+	partition := 1
+
+	tests := []struct {
+		name    string
+		batch   []BatchMessage
+		want    bool
+		wantErr bool
+	}{
+		{"none set", []BatchMessage{{}, {}}, false, false},
+		{"all set", []BatchMessage{{Partition: &partition}, {Partition: &partition}}, true, false},
+		{"partial mix", []BatchMessage{{Partition: &partition}, {}}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hasExplicitPartitions(tt.batch)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("hasExplicitPartitions() = nil error, want errPartialPartitions")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("hasExplicitPartitions(): %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("hasExplicitPartitions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
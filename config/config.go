@@ -0,0 +1,251 @@
+// Package config loads broker, SASL, and TLS settings for every Kafka
+// cluster the service is allowed to talk to, so handlers can target a
+// cluster by name instead of a hardcoded address.
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPathEnv is the environment variable used to locate the config file
+// when no path is given explicitly.
+const ConfigPathEnv = "KAFKA_SERVICE_CONFIG"
+
+const defaultConfigPath = "config.yaml"
+
+type SASLConfig struct {
+	Mechanism string `yaml:"mechanism"` // PLAIN, SCRAM-SHA-256, SCRAM-SHA-512
+	Username  string `yaml:"username"`
+	Password  string `yaml:"password"`
+}
+
+type TLSConfig struct {
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	CAFile             string `yaml:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// ProducerConfig tunes the writer pooled for a cluster: batching,
+// compression, and the acks level required for a write to be considered
+// durable.
+type ProducerConfig struct {
+	BatchSize      int    `yaml:"batch_size"`
+	BatchTimeoutMs int    `yaml:"batch_timeout_ms"`
+	Compression    string `yaml:"compression"`   // none, gzip, snappy, lz4, zstd
+	RequiredAcks   string `yaml:"required_acks"` // none, one, all
+}
+
+// ClusterConfig describes one named Kafka cluster alias.
+type ClusterConfig struct {
+	Brokers  []string        `yaml:"brokers"`
+	SASL     *SASLConfig     `yaml:"sasl,omitempty"`
+	TLS      *TLSConfig      `yaml:"tls,omitempty"`
+	Producer *ProducerConfig `yaml:"producer,omitempty"`
+}
+
+// TracingConfig selects the distributed tracing backend and its
+// per-backend settings, the same way the Traefik tracing integration lets
+// an operator pick Jaeger/Zipkin/OTLP behind one config block.
+type TracingConfig struct {
+	Backend     string  `yaml:"backend"` // jaeger, zipkin, otlp, or "" to disable
+	ServiceName string  `yaml:"service_name"`
+	Endpoint    string  `yaml:"endpoint"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Config is the root of the loaded configuration file.
+type Config struct {
+	DefaultCluster string                   `yaml:"default_cluster"`
+	Clusters       map[string]ClusterConfig `yaml:"clusters"`
+	Tracing        TracingConfig            `yaml:"tracing"`
+}
+
+// Load reads the config file at path, falling back to $KAFKA_SERVICE_CONFIG
+// and then ./config.yaml when path is empty.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(ConfigPathEnv)
+	}
+	if path == "" {
+		path = defaultConfigPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+
+	// Expand ${VAR} / $VAR references so secrets like SASL credentials can be
+	// kept out of the checked-in config file.
+	expanded := os.ExpandEnv(string(data))
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+
+	if len(cfg.Clusters) == 0 {
+		return nil, fmt.Errorf("config file %q does not define any clusters", path)
+	}
+	if cfg.DefaultCluster == "" {
+		for name := range cfg.Clusters {
+			cfg.DefaultCluster = name
+			break
+		}
+	}
+
+	return &cfg, nil
+}
+
+// ErrUnknownCluster is returned by Cluster when the requested name (or the
+// configured default) does not match any configured cluster. Callers can
+// match it with errors.Is to tell a bad client-supplied cluster name apart
+// from a downstream failure (dial, TLS, SASL) that happens once a valid
+// cluster is resolved.
+var ErrUnknownCluster = errors.New("unknown cluster")
+
+// Cluster resolves a named cluster, falling back to the configured default
+// when name is empty.
+func (c *Config) Cluster(name string) (ClusterConfig, error) {
+	if name == "" {
+		name = c.DefaultCluster
+	}
+
+	cluster, ok := c.Clusters[name]
+	if !ok {
+		return ClusterConfig{}, fmt.Errorf("%w: %q", ErrUnknownCluster, name)
+	}
+	return cluster, nil
+}
+
+// Dialer builds a kafka.Dialer configured with this cluster's SASL mechanism
+// and TLS settings, ready to use for kafka.Writer, kafka.Reader, and
+// kafka.Dial calls.
+func (cc ClusterConfig) Dialer() (*kafka.Dialer, error) {
+	dialer := &kafka.Dialer{
+		Timeout:   kafka.DefaultDialer.Timeout,
+		DualStack: true,
+	}
+
+	if cc.TLS != nil {
+		tlsConfig, err := cc.TLS.build()
+		if err != nil {
+			return nil, err
+		}
+		dialer.TLS = tlsConfig
+	}
+
+	if cc.SASL != nil {
+		mechanism, err := cc.SASL.build()
+		if err != nil {
+			return nil, err
+		}
+		dialer.SASLMechanism = mechanism
+	}
+
+	return dialer, nil
+}
+
+const (
+	defaultBatchSize      = 100
+	defaultBatchTimeoutMs = 1000
+)
+
+// WriterSettings resolves this cluster's producer tuning into the values a
+// kafka.Writer expects, applying sane defaults when unset. Required acks
+// defaults to "all" so a write is only acknowledged once every in-sync
+// replica has it.
+func (cc ClusterConfig) WriterSettings() (batchSize int, batchTimeoutMs int, compression kafka.Compression, requiredAcks kafka.RequiredAcks, err error) {
+	batchSize = defaultBatchSize
+	batchTimeoutMs = defaultBatchTimeoutMs
+	requiredAcks = kafka.RequireAll
+
+	if cc.Producer == nil {
+		return batchSize, batchTimeoutMs, compression, requiredAcks, nil
+	}
+
+	if cc.Producer.BatchSize > 0 {
+		batchSize = cc.Producer.BatchSize
+	}
+	if cc.Producer.BatchTimeoutMs > 0 {
+		batchTimeoutMs = cc.Producer.BatchTimeoutMs
+	}
+
+	switch cc.Producer.Compression {
+	case "", "none":
+		compression = 0
+	case "gzip":
+		compression = kafka.Gzip
+	case "snappy":
+		compression = kafka.Snappy
+	case "lz4":
+		compression = kafka.Lz4
+	case "zstd":
+		compression = kafka.Zstd
+	default:
+		return 0, 0, 0, 0, fmt.Errorf("unsupported compression %q", cc.Producer.Compression)
+	}
+
+	switch cc.Producer.RequiredAcks {
+	case "":
+		// keep the kafka.RequireAll default set above
+	case "none":
+		requiredAcks = kafka.RequireNone
+	case "one":
+		requiredAcks = kafka.RequireOne
+	case "all":
+		requiredAcks = kafka.RequireAll
+	default:
+		return 0, 0, 0, 0, fmt.Errorf("unsupported required_acks %q", cc.Producer.RequiredAcks)
+	}
+
+	return batchSize, batchTimeoutMs, compression, requiredAcks, nil
+}
+
+func (s *SASLConfig) build() (sasl.Mechanism, error) {
+	switch s.Mechanism {
+	case "PLAIN":
+		return plain.Mechanism{Username: s.Username, Password: s.Password}, nil
+	case "SCRAM-SHA-256":
+		return scram.Mechanism(scram.SHA256, s.Username, s.Password)
+	case "SCRAM-SHA-512":
+		return scram.Mechanism(scram.SHA512, s.Username, s.Password)
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q", s.Mechanism)
+	}
+}
+
+func (t *TLSConfig) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: t.InsecureSkipVerify}
+
+	if t.CAFile != "" {
+		caCert, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" && t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
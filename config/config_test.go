@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestWriterSettingsDefaults(t *testing.T) {
+	cc := ClusterConfig{}
+
+	batchSize, batchTimeoutMs, compression, requiredAcks, err := cc.WriterSettings()
+	if err != nil {
+		t.Fatalf("WriterSettings(): %v", err)
+	}
+	if batchSize != defaultBatchSize {
+		t.Errorf("batchSize = %d, want default %d", batchSize, defaultBatchSize)
+	}
+	if batchTimeoutMs != defaultBatchTimeoutMs {
+		t.Errorf("batchTimeoutMs = %d, want default %d", batchTimeoutMs, defaultBatchTimeoutMs)
+	}
+	if compression != 0 {
+		t.Errorf("compression = %v, want none", compression)
+	}
+	if requiredAcks != kafka.RequireAll {
+		t.Errorf("requiredAcks = %v, want RequireAll", requiredAcks)
+	}
+}
+
+func TestWriterSettingsCompression(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    kafka.Compression
+		wantErr bool
+	}{
+		{"empty", "", 0, false},
+		{"none", "none", 0, false},
+		{"gzip", "gzip", kafka.Gzip, false},
+		{"snappy", "snappy", kafka.Snappy, false},
+		{"lz4", "lz4", kafka.Lz4, false},
+		{"zstd", "zstd", kafka.Zstd, false},
+		{"unsupported", "bz2", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := ClusterConfig{Producer: &ProducerConfig{Compression: tt.value}}
+
+			_, _, compression, _, err := cc.WriterSettings()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("WriterSettings() with compression %q = nil error, want an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriterSettings() with compression %q: %v", tt.value, err)
+			}
+			if compression != tt.want {
+				t.Errorf("compression = %v, want %v", compression, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterSettingsRequiredAcks(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    kafka.RequiredAcks
+		wantErr bool
+	}{
+		{"empty keeps default", "", kafka.RequireAll, false},
+		{"none", "none", kafka.RequireNone, false},
+		{"one", "one", kafka.RequireOne, false},
+		{"all", "all", kafka.RequireAll, false},
+		{"unsupported", "quorum", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := ClusterConfig{Producer: &ProducerConfig{RequiredAcks: tt.value}}
+
+			_, _, _, requiredAcks, err := cc.WriterSettings()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("WriterSettings() with required_acks %q = nil error, want an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriterSettings() with required_acks %q: %v", tt.value, err)
+			}
+			if requiredAcks != tt.want {
+				t.Errorf("requiredAcks = %v, want %v", requiredAcks, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterSettingsOverridesBatchTuning(t *testing.T) {
+	cc := ClusterConfig{Producer: &ProducerConfig{BatchSize: 500, BatchTimeoutMs: 250}}
+
+	batchSize, batchTimeoutMs, _, _, err := cc.WriterSettings()
+	if err != nil {
+		t.Fatalf("WriterSettings(): %v", err)
+	}
+	if batchSize != 500 {
+		t.Errorf("batchSize = %d, want 500", batchSize)
+	}
+	if batchTimeoutMs != 250 {
+		t.Errorf("batchTimeoutMs = %d, want 250", batchTimeoutMs)
+	}
+}
+
+func TestDialerAppliesSASLAndTLS(t *testing.T) {
+	cc := ClusterConfig{
+		SASL: &SASLConfig{Mechanism: "PLAIN", Username: "user", Password: "pass"},
+		TLS:  &TLSConfig{InsecureSkipVerify: true},
+	}
+
+	dialer, err := cc.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer(): %v", err)
+	}
+	if dialer.SASLMechanism == nil {
+		t.Error("SASLMechanism = nil, want the configured PLAIN mechanism")
+	}
+	if dialer.TLS == nil || !dialer.TLS.InsecureSkipVerify {
+		t.Error("TLS = nil or InsecureSkipVerify unset, want the configured TLS config")
+	}
+}
+
+func TestDialerWithoutSASLOrTLS(t *testing.T) {
+	dialer, err := ClusterConfig{}.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer(): %v", err)
+	}
+	if dialer.SASLMechanism != nil {
+		t.Error("SASLMechanism = non-nil, want nil for a cluster with no SASL config")
+	}
+	if dialer.TLS != nil {
+		t.Error("TLS = non-nil, want nil for a cluster with no TLS config")
+	}
+}
+
+func TestDialerRejectsUnsupportedSASLMechanism(t *testing.T) {
+	cc := ClusterConfig{SASL: &SASLConfig{Mechanism: "GSSAPI"}}
+
+	if _, err := cc.Dialer(); err == nil {
+		t.Fatal("Dialer() with unsupported SASL mechanism = nil error, want an error")
+	}
+}
+
+func TestClusterUnknownNameIsErrUnknownCluster(t *testing.T) {
+	cfg := &Config{
+		DefaultCluster: "local",
+		Clusters:       map[string]ClusterConfig{"local": {Brokers: []string{"localhost:9092"}}},
+	}
+
+	_, err := cfg.Cluster("does-not-exist")
+	if !errors.Is(err, ErrUnknownCluster) {
+		t.Fatalf("Cluster() error = %v, want it to match ErrUnknownCluster", err)
+	}
+}
+
+func TestClusterKnownNameSucceeds(t *testing.T) {
+	cfg := &Config{
+		DefaultCluster: "local",
+		Clusters:       map[string]ClusterConfig{"local": {Brokers: []string{"localhost:9092"}}},
+	}
+
+	cluster, err := cfg.Cluster("")
+	if err != nil {
+		t.Fatalf("Cluster(\"\"): %v", err)
+	}
+	if len(cluster.Brokers) != 1 || cluster.Brokers[0] != "localhost:9092" {
+		t.Fatalf("Cluster(\"\") = %+v, want the default cluster", cluster)
+	}
+}
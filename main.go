@@ -3,50 +3,40 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
-	"github.com/go-playground/validator"
 	"github.com/gorilla/mux"
-	"github.com/mitchellh/mapstructure"
+	"github.com/mffonseca/golang-kafka-service-manager/config"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-var writer *kafka.Writer
+var (
+	cfg        *config.Config
+	writerPool *WriterPool
+)
 
 type Message struct {
 	Type    string      `json:"type"`
 	Content interface{} `json:"content"`
 }
 
-type PaymentMessage struct {
-	Id     string `json:"id" validate:"required"`
-	Amount int    `json:"amount" validate:"required"`
-	Status string `json:"status" validate:"required"`
-}
-
-type UserMessage struct {
-	Name    string `json:"name" validate:"required"`
-	Email   string `json:"email" validate:"required"`
-	Phone   string `json:"phone" validate:"required"`
-	Address string `json:"address"`
-}
-
-func createWriter(topic string) {
-	writer = kafka.NewWriter(kafka.WriterConfig{
-		Brokers:  []string{"localhost:9092"},
-		Topic:    topic,
-		Balancer: &kafka.LeastBytes{},
-	})
-}
-
 func createMessageHandler(w http.ResponseWriter, r *http.Request) {
 
+	ctx, span := tracer.Start(r.Context(), "kafka.produce")
+	defer span.End()
+
 	params := mux.Vars(r)
 
 	topic := params["topic"]
+	cluster := r.URL.Query().Get("cluster")
+	span.SetAttributes(attribute.String("messaging.destination", topic))
 
 	if strings.TrimSpace(topic) == "" {
 		log.Error("Missing topic")
@@ -54,9 +44,12 @@ func createMessageHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createWriter(topic)
-
-	defer writer.Close()
+	writer, err := writerPool.Get(cluster, topic)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to get writer")
+		handleBadRequest(w)
+		return
+	}
 
 	var msg Message
 
@@ -66,83 +59,58 @@ func createMessageHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var messageJson []byte
-	var err error
-
-	switch msg.Type {
-	case "new_user":
-		var userMessage UserMessage
-		contentMap, ok := msg.Content.(map[string]interface{})
-		if !ok {
-			log.WithField("error", "content is not a map").Error("Invalid content")
-			handleBadRequest(w)
-			return
-		}
-
-		err = mapstructure.Decode(contentMap, &userMessage)
-		if err != nil {
-			log.WithField("error", err).Error("Failed to decode user message")
-			handleBadRequest(w)
-			return
-		}
-
-		validate := validator.New()
-		err = validate.Struct(userMessage)
-		if err != nil {
-			log.WithField("error", err).Error("Validation failed")
-			handleBadRequest(w)
-			return
-		}
-	case "new_payment":
-		var paymentMessage PaymentMessage
-		contentMap, ok := msg.Content.(map[string]interface{})
-		if !ok {
-			log.WithField("error", "content is not a map").Error("Invalid content")
-			handleBadRequest(w)
-			return
-		}
-
-		err = mapstructure.Decode(contentMap, &paymentMessage)
-		if err != nil {
-			log.WithField("error", err).Error("Failed to decode payment message")
-			handleBadRequest(w)
-			return
-		}
-
-		validate := validator.New()
-		err = validate.Struct(paymentMessage)
-		if err != nil {
-			log.WithField("error", err).Error("Validation failed")
-			handleBadRequest(w)
-			return
-		}
-	default:
-		log.WithField("type", msg.Type).Error("Invalid message type")
+	contentJson, err := json.Marshal(msg.Content)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to marshal message content")
+		handleBadRequest(w)
+		return
+	}
+
+	schema, ok := schemaRegistry.Latest(msg.Type)
+	if !ok {
+		log.WithField("type", msg.Type).Error("No schema registered for message type")
+		handleBadRequest(w)
+		return
+	}
+
+	if err := schema.Validate(contentJson); err != nil {
+		validationFailures.WithLabelValues(msg.Type).Inc()
+		log.WithField("error", err).Error("Validation failed")
 		handleBadRequest(w)
 		return
 	}
 
-	err = writer.WriteMessages(context.Background(),
-		kafka.Message{
-			Value: messageJson,
-		},
-	)
+	messageJson := frameRecord(schema.ID, contentJson)
+
+	kafkaMsg := kafka.Message{Value: messageJson}
+	injectTraceContext(ctx, &kafkaMsg)
+
+	start := time.Now()
+	err = writer.WriteMessages(ctx, kafkaMsg)
+	publishLatency.WithLabelValues(topic).Observe(time.Since(start).Seconds())
 
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		log.WithField("error", err).Error("Failed to write message")
 		handleInternalError(w)
 		return
 	}
 
+	messagesProduced.WithLabelValues(topic).Inc()
+
 	w.WriteHeader(http.StatusCreated)
 	fmt.Fprintf(w, "Message created successfully")
 }
 
 func createTopicHandler(w http.ResponseWriter, r *http.Request) {
 
+	_, span := tracer.Start(r.Context(), "kafka.create_topic")
+	defer span.End()
+
 	params := mux.Vars(r)
 
 	topicName := params["topic"]
+	span.SetAttributes(attribute.String("messaging.destination", topicName))
 
 	if strings.TrimSpace(topicName) == "" {
 		log.Error("Missing topic name")
@@ -150,14 +118,12 @@ func createTopicHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := kafka.Dial("tcp", "localhost:9092")
-
+	conn, err := dialCluster(r.URL.Query().Get("cluster"))
 	if err != nil {
 		log.WithField("error", err).Error("Failed to dial leader")
-		handleInternalError(w)
+		handleDialError(w, err)
 		return
 	}
-
 	defer conn.Close()
 
 	topicConfig := kafka.TopicConfig{
@@ -168,6 +134,8 @@ func createTopicHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = conn.CreateTopics(topicConfig)
 	if err != nil {
+		topicCreateErrors.WithLabelValues(topicName).Inc()
+		span.SetStatus(codes.Error, err.Error())
 		log.WithField("error", err).Error("Failed to create topic")
 		handleInternalError(w)
 		return
@@ -178,10 +146,10 @@ func createTopicHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func listTopicsHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := kafka.Dial("tcp", "localhost:9092")
+	conn, err := dialCluster(r.URL.Query().Get("cluster"))
 	if err != nil {
 		log.WithField("error", err).Error("Failed to dial leader")
-		handleInternalError(w)
+		handleDialError(w, err)
 		return
 	}
 	defer conn.Close()
@@ -202,44 +170,64 @@ func listTopicsHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "Topics: ", strings.Join(getKeysFromMap(topics), ", "))
 }
 
-func listMessagesHandler(w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	topic := params["topic"]
-
-	if strings.TrimSpace(topic) == "" {
-		log.Error("Missing topic")
-		handleBadRequest(w)
-		return
+// dialCluster resolves the named cluster (or the configured default when
+// name is empty) and dials its first broker with the cluster's SASL/TLS
+// settings applied. An unknown cluster name surfaces config.ErrUnknownCluster;
+// any other error comes from actually building the dialer or reaching the
+// broker.
+func dialCluster(name string) (*kafka.Conn, error) {
+	clusterConfig, err := cfg.Cluster(name)
+	if err != nil {
+		return nil, err
 	}
 
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:   []string{"localhost:9092"},
-		Topic:     topic,
-		Partition: 0,
-		MinBytes:  10e3, // 10KB
-		MaxBytes:  10e6, // 10MB
-	})
-	defer reader.Close()
-
-	for {
-		m, err := reader.ReadMessage(context.Background())
-		if err != nil {
-			break
-		}
-		fmt.Fprintf(w, "message at offset %d: %s = %s\n", m.Offset, string(m.Key), string(m.Value))
+	dialer, err := clusterConfig.Dialer()
+	if err != nil {
+		return nil, err
 	}
+
+	return dialer.Dial("tcp", clusterConfig.Brokers[0])
 }
 
 func main() {
 
+	loadedConfig, err := config.Load("")
+	if err != nil {
+		log.WithField("error", err).Error("Failed to load config")
+		return
+	}
+	cfg = loadedConfig
+	writerPool = NewWriterPool(cfg)
+	defer writerPool.Close()
+	subscriptionManager = NewSubscriptionManager(subscriptionsStateFile)
+
+	shutdownTracing, err := initTracing(cfg.Tracing)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to initialize tracing")
+		return
+	}
+	defer shutdownTracing(context.Background())
+
 	r := mux.NewRouter()
 
 	r.HandleFunc("/create/{topic}", createTopicHandler).Methods("POST")
 
 	r.HandleFunc("/publish/{topic}", createMessageHandler).Methods("POST")
+	r.HandleFunc("/publish/{topic}/batch", batchPublishHandler).Methods("POST")
+	r.HandleFunc("/publish/stats", publishStatsHandler).Methods("GET")
 
 	r.HandleFunc("/topics", listTopicsHandler).Methods("GET")
-	r.HandleFunc("/messages/{topic}", listMessagesHandler).Methods("GET")
+	r.HandleFunc("/messages/{topic}/tail", tailMessagesHandler).Methods("GET")
+	r.HandleFunc("/messages/{topic}/ws", wsMessagesHandler).Methods("GET")
+
+	r.HandleFunc("/subscriptions", createSubscriptionHandler).Methods("POST")
+	r.HandleFunc("/subscriptions/{id}", getSubscriptionHandler).Methods("GET")
+	r.HandleFunc("/subscriptions/{id}", deleteSubscriptionHandler).Methods("DELETE")
+
+	r.HandleFunc("/schemas/{name}", registerSchemaHandler).Methods("POST")
+	r.HandleFunc("/schemas", listSchemasHandler).Methods("GET")
+
+	r.Handle("/metrics", metricsHandler()).Methods("GET")
 
 	if err := http.ListenAndServe(":8080", r); err != nil {
 		log.WithField("error", err).Error("Failed to start server")
@@ -264,6 +252,17 @@ func handleInternalError(w http.ResponseWriter) {
 	handleError(w, http.StatusInternalServerError)
 }
 
+// handleDialError maps a dialCluster failure to the right status: an unknown
+// cluster name is the caller's mistake, but a dialer/TLS/SASL build failure
+// or an unreachable broker is ours, not theirs.
+func handleDialError(w http.ResponseWriter, err error) {
+	if errors.Is(err, config.ErrUnknownCluster) {
+		handleBadRequest(w)
+		return
+	}
+	handleInternalError(w)
+}
+
 func getKeysFromMap(m map[string]bool) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	messagesProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_service_messages_produced_total",
+		Help: "Number of messages successfully produced, by topic.",
+	}, []string{"topic"})
+
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_service_messages_consumed_total",
+		Help: "Number of messages successfully consumed, by topic.",
+	}, []string{"topic"})
+
+	publishLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_service_publish_latency_seconds",
+		Help:    "Time spent producing a message, by topic.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	validationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_service_validation_failures_total",
+		Help: "Number of message payloads that failed schema validation, by message type.",
+	}, []string{"type"})
+
+	topicCreateErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_service_topic_create_errors_total",
+		Help: "Number of failed topic creation requests, by topic.",
+	}, []string{"topic"})
+)
+
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
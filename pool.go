@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mffonseca/golang-kafka-service-manager/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// writerKey identifies a single long-lived writer by the cluster and topic
+// it targets. direct distinguishes the balancer-less writer batch publish
+// uses to honor an explicit per-message partition from the normal
+// balancer-routed writer, since a *kafka.Writer only honors
+// kafka.Message.Partition when it has no Balancer at all.
+type writerKey struct {
+	cluster string
+	topic   string
+	direct  bool
+}
+
+// WriterPool keeps one *kafka.Writer per (cluster, topic) alive for the
+// life of the process instead of opening and closing a writer per request.
+type WriterPool struct {
+	mu      sync.Mutex
+	writers map[writerKey]*kafka.Writer
+	cfg     *config.Config
+}
+
+func NewWriterPool(cfg *config.Config) *WriterPool {
+	return &WriterPool{
+		writers: make(map[writerKey]*kafka.Writer),
+		cfg:     cfg,
+	}
+}
+
+// Get returns the balancer-routed writer for (cluster, topic), creating it
+// on first use.
+func (p *WriterPool) Get(cluster, topic string) (*kafka.Writer, error) {
+	return p.get(writerKey{cluster: cluster, topic: topic})
+}
+
+// GetDirect returns the writer for (cluster, topic) that routes messages to
+// the partition named in kafka.Message.Partition instead of balancing them,
+// creating it on first use. Use this only when every message in a request
+// sets an explicit partition.
+func (p *WriterPool) GetDirect(cluster, topic string) (*kafka.Writer, error) {
+	return p.get(writerKey{cluster: cluster, topic: topic, direct: true})
+}
+
+func (p *WriterPool) get(key writerKey) (*kafka.Writer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w, ok := p.writers[key]; ok {
+		return w, nil
+	}
+
+	w, err := buildWriter(p.cfg, key.cluster, key.topic, key.direct)
+	if err != nil {
+		return nil, err
+	}
+
+	p.writers[key] = w
+	return w, nil
+}
+
+// buildWriter resolves a cluster's brokers, SASL/TLS dialer, and producer
+// tuning into a ready-to-use writer. Every writer shares dispatchCompletion
+// so a batch publish can recover real partition/offset results regardless of
+// which pooled writer instance carries its messages; direct, when true,
+// leaves the writer without a Balancer so kafka-go routes by
+// kafka.Message.Partition instead of balancing.
+func buildWriter(cfg *config.Config, cluster, topic string, direct bool) (*kafka.Writer, error) {
+	clusterConfig, err := cfg.Cluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := clusterConfig.Dialer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dialer for cluster %q: %w", cluster, err)
+	}
+
+	batchSize, batchTimeoutMs, compression, requiredAcks, err := clusterConfig.WriterSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve writer settings for cluster %q: %w", cluster, err)
+	}
+
+	var balancer kafka.Balancer
+	if !direct {
+		balancer = &kafka.LeastBytes{}
+	}
+
+	return &kafka.Writer{
+		Addr:         kafka.TCP(clusterConfig.Brokers...),
+		Topic:        topic,
+		Balancer:     balancer,
+		BatchSize:    batchSize,
+		BatchTimeout: time.Duration(batchTimeoutMs) * time.Millisecond,
+		Compression:  compression,
+		RequiredAcks: requiredAcks,
+		Transport: &kafka.Transport{
+			SASL: dialer.SASLMechanism,
+			TLS:  dialer.TLS,
+		},
+		Completion: dispatchCompletion,
+	}, nil
+}
+
+// Stats reports each pooled writer's kafka.WriterStats, keyed by
+// "cluster/topic", for GET /publish/stats.
+func (p *WriterPool) Stats() map[string]kafka.WriterStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make(map[string]kafka.WriterStats, len(p.writers))
+	for key, w := range p.writers {
+		name := fmt.Sprintf("%s/%s", key.cluster, key.topic)
+		if key.direct {
+			name += "/direct"
+		}
+		stats[name] = w.Stats()
+	}
+	return stats
+}
+
+// Close shuts down every pooled writer, flushing buffered messages.
+func (p *WriterPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for key, w := range p.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close writer for %s/%s: %w", key.cluster, key.topic, err)
+		}
+	}
+	return firstErr
+}
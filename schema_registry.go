@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schemaMagicByte is the leading byte of a framed record, mirroring the
+// Confluent wire format (magic byte + 4-byte schema id + payload).
+const schemaMagicByte = 0x0
+
+// SchemaFormat identifies the encoding a schema describes.
+//
+// KNOWN GAP: only FormatJSONSchema is implemented. The registry validates
+// payloads against a JSON Schema and createMessageHandler always produces
+// plain JSON framed with the schema id. FormatAvro and FormatProtobuf exist
+// as named constants because registered schemas record which format they
+// were uploaded as, but the registry has no Avro/Protobuf encoder or
+// validator - Register rejects both rather than silently treating them as
+// JSON. Encoding to those wire formats is unimplemented, not deferred by
+// design; it would need a real codec per format before it could work.
+type SchemaFormat string
+
+const (
+	FormatJSONSchema SchemaFormat = "json"
+	FormatAvro       SchemaFormat = "avro"
+	FormatProtobuf   SchemaFormat = "protobuf"
+)
+
+// SchemaVersion is one registered version of a named schema.
+type SchemaVersion struct {
+	ID      int          `json:"id"`
+	Version int          `json:"version"`
+	Format  SchemaFormat `json:"format"`
+	Schema  []byte       `json:"-"`
+
+	compiled *gojsonschema.Schema
+}
+
+// SchemaRegistry stores every registered schema by name, keeping all
+// versions so producers can evolve a schema without breaking old consumers.
+type SchemaRegistry struct {
+	mu     sync.RWMutex
+	byName map[string][]*SchemaVersion
+	byID   map[int]*SchemaVersion
+	nextID int
+}
+
+var schemaRegistry = NewSchemaRegistry()
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		byName: make(map[string][]*SchemaVersion),
+		byID:   make(map[int]*SchemaVersion),
+		nextID: 1,
+	}
+}
+
+func (r *SchemaRegistry) Register(name string, format SchemaFormat, schema []byte) (*SchemaVersion, error) {
+	if format != FormatJSONSchema {
+		return nil, fmt.Errorf("unsupported schema format %q: only %q is implemented", format, FormatJSONSchema)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	sv := &SchemaVersion{
+		ID:       r.nextID,
+		Version:  len(r.byName[name]) + 1,
+		Format:   format,
+		Schema:   schema,
+		compiled: compiled,
+	}
+
+	r.nextID++
+	r.byName[name] = append(r.byName[name], sv)
+	r.byID[sv.ID] = sv
+
+	return sv, nil
+}
+
+// Latest returns the most recently registered version of a named schema.
+func (r *SchemaRegistry) Latest(name string) (*SchemaVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.byName[name]
+	if len(versions) == 0 {
+		return nil, false
+	}
+	return versions[len(versions)-1], true
+}
+
+func (r *SchemaRegistry) ByID(id int) (*SchemaVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sv, ok := r.byID[id]
+	return sv, ok
+}
+
+func (r *SchemaRegistry) List() map[string][]*SchemaVersion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string][]*SchemaVersion, len(r.byName))
+	for name, versions := range r.byName {
+		out[name] = versions
+	}
+	return out
+}
+
+// Validate checks payload against the compiled JSON schema.
+func (sv *SchemaVersion) Validate(payload []byte) error {
+	result, err := sv.compiled.Validate(gojsonschema.NewBytesLoader(payload))
+	if err != nil {
+		return err
+	}
+	if !result.Valid() {
+		errs := make([]string, 0, len(result.Errors()))
+		for _, e := range result.Errors() {
+			errs = append(errs, e.String())
+		}
+		return fmt.Errorf("schema validation failed: %v", errs)
+	}
+	return nil
+}
+
+// frameRecord writes a Confluent-style framed record: magic byte + 4-byte
+// schema id (big endian) + payload.
+func frameRecord(schemaID int, payload []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(schemaMagicByte)
+	binary.Write(buf, binary.BigEndian, int32(schemaID))
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// registerSchemaHandler stores the request body as a new version of the
+// named schema. format defaults to "json" and is the only format that
+// actually works today - see the KNOWN GAP note on SchemaFormat.
+func registerSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	name := params["name"]
+
+	format := SchemaFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = FormatJSONSchema
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		log.WithField("error", err).Error("Failed to read schema body")
+		handleBadRequest(w)
+		return
+	}
+
+	sv, err := schemaRegistry.Register(name, format, body)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to register schema")
+		handleBadRequest(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sv)
+}
+
+func listSchemasHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(schemaRegistry.List())
+}
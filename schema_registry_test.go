@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestSchemaRegistryRegisterRejectsNonJSONFormats(t *testing.T) {
+	schema := []byte(`{"type": "object"}`)
+
+	tests := []struct {
+		name   string
+		format SchemaFormat
+	}{
+		{"avro", FormatAvro},
+		{"protobuf", FormatProtobuf},
+		{"unknown", SchemaFormat("xml")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSchemaRegistry()
+			if _, err := r.Register("widget", tt.format, schema); err == nil {
+				t.Fatalf("Register(%q) = nil error, want an error", tt.format)
+			}
+		})
+	}
+}
+
+func TestSchemaRegistryRegisterRejectsInvalidJSONSchema(t *testing.T) {
+	r := NewSchemaRegistry()
+	if _, err := r.Register("widget", FormatJSONSchema, []byte(`not json`)); err == nil {
+		t.Fatal("Register() with malformed JSON schema = nil error, want an error")
+	}
+}
+
+func TestSchemaRegistryRegisterVersionsIncrementPerName(t *testing.T) {
+	r := NewSchemaRegistry()
+	schema := []byte(`{"type": "object"}`)
+
+	first, err := r.Register("widget", FormatJSONSchema, schema)
+	if err != nil {
+		t.Fatalf("Register() first version: %v", err)
+	}
+	second, err := r.Register("widget", FormatJSONSchema, schema)
+	if err != nil {
+		t.Fatalf("Register() second version: %v", err)
+	}
+
+	if first.Version != 1 || second.Version != 2 {
+		t.Fatalf("got versions %d, %d; want 1, 2", first.Version, second.Version)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("expected distinct schema ids, both were %d", first.ID)
+	}
+
+	latest, ok := r.Latest("widget")
+	if !ok || latest.ID != second.ID {
+		t.Fatalf("Latest() = %+v, %v; want the second registered version", latest, ok)
+	}
+}
+
+func TestSchemaVersionValidate(t *testing.T) {
+	r := NewSchemaRegistry()
+	schema := []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"name": {"type": "string"}}
+	}`)
+
+	sv, err := r.Register("widget", FormatJSONSchema, schema)
+	if err != nil {
+		t.Fatalf("Register(): %v", err)
+	}
+
+	if err := sv.Validate([]byte(`{"name": "gizmo"}`)); err != nil {
+		t.Errorf("Validate() on a conforming payload = %v, want nil", err)
+	}
+	if err := sv.Validate([]byte(`{}`)); err == nil {
+		t.Error("Validate() on a payload missing a required field = nil, want an error")
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// tailedMessage is what both the SSE and WebSocket endpoints emit per record.
+type tailedMessage struct {
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// newTailReader builds a reader for GET /messages/{topic}/tail and
+// GET /messages/{topic}/ws from their shared query params: from
+// (earliest|latest|<offset>|<timestamp>), partition, and group (for
+// balanced consumer group tailing instead of a single partition).
+func newTailReader(r *http.Request, topic string) (*kafka.Reader, error) {
+	clusterConfig, err := cfg.Cluster(r.URL.Query().Get("cluster"))
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := clusterConfig.Dialer()
+	if err != nil {
+		return nil, err
+	}
+
+	readerConfig := kafka.ReaderConfig{
+		Brokers:  clusterConfig.Brokers,
+		Dialer:   dialer,
+		Topic:    topic,
+		MinBytes: 1,
+		MaxBytes: 10e6,
+	}
+
+	if group := r.URL.Query().Get("group"); group != "" {
+		readerConfig.GroupID = group
+	} else if partitionParam := r.URL.Query().Get("partition"); partitionParam != "" {
+		partition, err := strconv.Atoi(partitionParam)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition %q: %w", partitionParam, err)
+		}
+		readerConfig.Partition = partition
+	}
+
+	reader := kafka.NewReader(readerConfig)
+
+	if from := r.URL.Query().Get("from"); from != "" && readerConfig.GroupID == "" {
+		if err := seekTo(r.Context(), reader, from); err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
+
+func seekTo(ctx context.Context, reader *kafka.Reader, from string) error {
+	switch from {
+	case "earliest":
+		return reader.SetOffset(kafka.FirstOffset)
+	case "latest":
+		return reader.SetOffset(kafka.LastOffset)
+	}
+
+	if offset, err := strconv.ParseInt(from, 10, 64); err == nil {
+		return reader.SetOffset(offset)
+	}
+
+	if ts, err := time.Parse(time.RFC3339, from); err == nil {
+		return reader.SetOffsetAt(ctx, ts)
+	}
+
+	return fmt.Errorf("invalid from=%q: must be earliest, latest, an offset, or an RFC3339 timestamp", from)
+}
+
+// compileFilter builds the key/value regex filter shared by the tail
+// endpoints (a lighter-weight cousin of the jq-style filter on subscriptions).
+func compileFilter(raw string) (*regexp.Regexp, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	return regexp.Compile(raw)
+}
+
+func matchesFilter(filter *regexp.Regexp, msg kafka.Message) bool {
+	if filter == nil {
+		return true
+	}
+	return filter.Match(msg.Key) || filter.Match(msg.Value)
+}
+
+// tailMessagesHandler streams a topic as Server-Sent Events, flushing after
+// every record and stopping as soon as the client disconnects.
+func tailMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	topic := params["topic"]
+
+	filter, err := compileFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		log.WithField("error", err).Error("Invalid filter")
+		handleBadRequest(w)
+		return
+	}
+
+	reader, err := newTailReader(r, topic)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to create tail reader")
+		handleBadRequest(w)
+		return
+	}
+	defer reader.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("Streaming unsupported")
+		handleInternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return
+		}
+
+		if !matchesFilter(filter, msg) {
+			continue
+		}
+
+		recordConsumed(ctx, topic, msg)
+
+		payload, err := json.Marshal(tailedMessage{
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Key:       string(msg.Key),
+			Value:     string(msg.Value),
+		})
+		if err != nil {
+			log.WithField("error", err).Error("Failed to marshal tailed message")
+			continue
+		}
+
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		flusher.Flush()
+	}
+}
+
+// wsMessagesHandler upgrades to a WebSocket and streams a topic with the
+// same from/partition/group/filter semantics as tailMessagesHandler.
+func wsMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	topic := params["topic"]
+
+	filter, err := compileFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		log.WithField("error", err).Error("Invalid filter")
+		handleBadRequest(w)
+		return
+	}
+
+	reader, err := newTailReader(r, topic)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to create tail reader")
+		handleBadRequest(w)
+		return
+	}
+	defer reader.Close()
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to upgrade to websocket")
+		return
+	}
+	defer conn.Close()
+
+	ctx := r.Context()
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return
+		}
+
+		if !matchesFilter(filter, msg) {
+			continue
+		}
+
+		recordConsumed(ctx, topic, msg)
+
+		payload, err := json.Marshal(tailedMessage{
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Key:       string(msg.Key),
+			Value:     string(msg.Value),
+		})
+		if err != nil {
+			log.WithField("error", err).Error("Failed to marshal tailed message")
+			continue
+		}
+
+		if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			log.WithField("error", err).Error("Failed to write to websocket")
+			return
+		}
+	}
+}
+
+// recordConsumed continues the producer's trace (if a traceparent header is
+// present), records a consume span, and increments the consume counter.
+func recordConsumed(ctx context.Context, topic string, msg kafka.Message) {
+	msgCtx := extractTraceContext(ctx, msg)
+	_, span := tracer.Start(msgCtx, "kafka.consume")
+	span.SetAttributes(attribute.String("messaging.destination", topic))
+	span.End()
+
+	messagesConsumed.WithLabelValues(topic).Inc()
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const subscriptionsStateFile = "subscriptions.json"
+
+// deadLetterTopic is where messages are routed once all delivery retries
+// for a subscription are exhausted.
+const deadLetterTopic = "dead-letter"
+
+// TargetType selects where a subscription delivers matched messages, mirroring
+// the filter -> deliver-to-target shape of an "info job".
+type TargetType string
+
+const (
+	TargetHTTP  TargetType = "http"
+	TargetFile  TargetType = "file"
+	TargetKafka TargetType = "kafka"
+)
+
+// SubscriptionStatus reports whether a subscription's consumer goroutine is
+// still running.
+type SubscriptionStatus string
+
+const (
+	StatusRunning SubscriptionStatus = "running"
+	StatusStopped SubscriptionStatus = "stopped"
+)
+
+// Topic, GroupID, Target, and Endpoint are required fields, enforced by hand
+// in createSubscriptionHandler rather than by struct tags - this module does
+// not depend on a validator library.
+type Subscription struct {
+	ID       string     `json:"id"`
+	Cluster  string     `json:"cluster"`
+	Topic    string     `json:"topic"`
+	GroupID  string     `json:"group_id"`
+	Filter   string     `json:"filter"`
+	Target   TargetType `json:"target"`
+	Endpoint string     `json:"endpoint"`
+
+	cancel context.CancelFunc
+
+	statusMu  sync.Mutex
+	status    SubscriptionStatus
+	lastError string
+}
+
+// setStatus records why runSubscription stopped (or that it's running), so
+// GET /subscriptions/{id} can tell a dead consumer goroutine apart from a
+// healthy one instead of reporting both as fine.
+func (s *Subscription) setStatus(status SubscriptionStatus, err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	s.status = status
+	if err != nil {
+		s.lastError = err.Error()
+	}
+}
+
+// MarshalJSON snapshots the subscription's status under lock, so encoding it
+// for a response never races with runSubscription updating it concurrently.
+func (s *Subscription) MarshalJSON() ([]byte, error) {
+	s.statusMu.Lock()
+	status, lastError := s.status, s.lastError
+	s.statusMu.Unlock()
+
+	type subscriptionJSON struct {
+		ID        string             `json:"id"`
+		Cluster   string             `json:"cluster"`
+		Topic     string             `json:"topic"`
+		GroupID   string             `json:"group_id"`
+		Filter    string             `json:"filter"`
+		Target    TargetType         `json:"target"`
+		Endpoint  string             `json:"endpoint"`
+		Status    SubscriptionStatus `json:"status"`
+		LastError string             `json:"last_error,omitempty"`
+	}
+
+	return json.Marshal(subscriptionJSON{
+		ID:        s.ID,
+		Cluster:   s.Cluster,
+		Topic:     s.Topic,
+		GroupID:   s.GroupID,
+		Filter:    s.Filter,
+		Target:    s.Target,
+		Endpoint:  s.Endpoint,
+		Status:    status,
+		LastError: lastError,
+	})
+}
+
+// SubscriptionManager owns the set of running subscriptions and persists
+// them so they can be restarted after a process restart.
+type SubscriptionManager struct {
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	stateFile     string
+}
+
+var subscriptionManager *SubscriptionManager
+
+func NewSubscriptionManager(stateFile string) *SubscriptionManager {
+	m := &SubscriptionManager{
+		subscriptions: make(map[string]*Subscription),
+		stateFile:     stateFile,
+	}
+	m.restore()
+	return m
+}
+
+// restore reloads subscriptions from disk and resumes them, so an in-flight
+// tail survives a process restart.
+func (m *SubscriptionManager) restore() {
+	data, err := os.ReadFile(m.stateFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithField("error", err).Error("Failed to read subscriptions state file")
+		}
+		return
+	}
+
+	var subs []*Subscription
+	if err := json.Unmarshal(data, &subs); err != nil {
+		log.WithField("error", err).Error("Failed to parse subscriptions state file")
+		return
+	}
+
+	for _, sub := range subs {
+		m.mu.Lock()
+		m.subscriptions[sub.ID] = sub
+		m.mu.Unlock()
+		m.start(sub)
+	}
+}
+
+func (m *SubscriptionManager) persist() {
+	m.mu.Lock()
+	subs := make([]*Subscription, 0, len(m.subscriptions))
+	for _, sub := range m.subscriptions {
+		subs = append(subs, sub)
+	}
+	m.mu.Unlock()
+
+	data, err := json.Marshal(subs)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to marshal subscriptions state")
+		return
+	}
+
+	if err := os.WriteFile(m.stateFile, data, 0644); err != nil {
+		log.WithField("error", err).Error("Failed to write subscriptions state file")
+	}
+}
+
+func (m *SubscriptionManager) Create(sub *Subscription) {
+	m.mu.Lock()
+	m.subscriptions[sub.ID] = sub
+	m.mu.Unlock()
+
+	m.start(sub)
+	m.persist()
+}
+
+func (m *SubscriptionManager) Get(id string) (*Subscription, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.subscriptions[id]
+	return sub, ok
+}
+
+func (m *SubscriptionManager) Delete(id string) bool {
+	m.mu.Lock()
+	sub, ok := m.subscriptions[id]
+	if ok {
+		delete(m.subscriptions, id)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	if sub.cancel != nil {
+		sub.cancel()
+	}
+	m.persist()
+	return true
+}
+
+// start launches the consumer loop for a subscription in its own goroutine.
+func (m *SubscriptionManager) start(sub *Subscription) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub.cancel = cancel
+	sub.setStatus(StatusRunning, nil)
+
+	go runSubscription(ctx, sub)
+}
+
+// runSubscription polls a consumer group reader, filters each record, and
+// delivers matches with exponential backoff, committing offsets only after a
+// successful delivery. Messages that exhaust their retries are routed to the
+// dead-letter topic instead of being dropped.
+func runSubscription(ctx context.Context, sub *Subscription) {
+	clusterConfig, err := cfg.Cluster(sub.Cluster)
+	if err != nil {
+		log.WithField("error", err).WithField("subscription", sub.ID).Error("Unknown cluster")
+		sub.setStatus(StatusStopped, err)
+		return
+	}
+
+	dialer, err := clusterConfig.Dialer()
+	if err != nil {
+		log.WithField("error", err).WithField("subscription", sub.ID).Error("Failed to build dialer")
+		sub.setStatus(StatusStopped, err)
+		return
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: clusterConfig.Brokers,
+		Dialer:  dialer,
+		Topic:   sub.Topic,
+		GroupID: sub.GroupID,
+	})
+	defer reader.Close()
+
+	dlq, err := writerPool.Get(sub.Cluster, deadLetterTopic)
+	if err != nil {
+		log.WithField("error", err).WithField("subscription", sub.ID).Error("Failed to get dead letter writer")
+		sub.setStatus(StatusStopped, err)
+		return
+	}
+
+	var filterRegexp *regexp.Regexp
+	if sub.Filter != "" {
+		var err error
+		filterRegexp, err = regexp.Compile(sub.Filter)
+		if err != nil {
+			log.WithField("error", err).WithField("subscription", sub.ID).Error("Invalid filter expression")
+			sub.setStatus(StatusStopped, err)
+			return
+		}
+	}
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				sub.setStatus(StatusStopped, nil)
+				return
+			}
+			log.WithField("error", err).WithField("subscription", sub.ID).Error("Failed to fetch message")
+			sub.setStatus(StatusStopped, err)
+			return
+		}
+
+		if filterRegexp != nil && !filterRegexp.Match(msg.Value) {
+			reader.CommitMessages(ctx, msg)
+			continue
+		}
+
+		if err := deliverWithBackoff(ctx, sub, msg); err != nil {
+			log.WithField("error", err).WithField("subscription", sub.ID).Error("Delivery failed permanently, routing to dead letter")
+			if dlqErr := dlq.WriteMessages(ctx, kafka.Message{Key: msg.Key, Value: msg.Value}); dlqErr != nil {
+				log.WithField("error", dlqErr).WithField("subscription", sub.ID).Error("Failed to write to dead letter topic")
+			}
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			log.WithField("error", err).WithField("subscription", sub.ID).Error("Failed to commit offset")
+		}
+	}
+}
+
+const maxDeliveryAttempts = 5
+
+func deliverWithBackoff(ctx context.Context, sub *Subscription, msg kafka.Message) error {
+	backoff := 500 * time.Millisecond
+
+	var err error
+	for attempt := 0; attempt < maxDeliveryAttempts; attempt++ {
+		if err = deliver(ctx, sub, msg); err == nil {
+			return nil
+		}
+
+		log.WithField("error", err).WithField("attempt", attempt+1).WithField("subscription", sub.ID).Warn("Delivery attempt failed")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
+
+func deliver(ctx context.Context, sub *Subscription, msg kafka.Message) error {
+	switch sub.Target {
+	case TargetHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(msg.Value))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}
+		return nil
+	case TargetFile:
+		f, err := os.OpenFile(sub.Endpoint, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.Write(append(msg.Value, '\n'))
+		return err
+	case TargetKafka:
+		w, err := writerPool.Get(sub.Cluster, sub.Endpoint)
+		if err != nil {
+			return err
+		}
+		return w.WriteMessages(ctx, kafka.Message{Key: msg.Key, Value: msg.Value})
+	default:
+		return fmt.Errorf("unknown target type %q", sub.Target)
+	}
+}
+
+func createSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var sub Subscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		log.WithField("error", err).Error("Failed to decode request body")
+		handleBadRequest(w)
+		return
+	}
+
+	if strings.TrimSpace(sub.Topic) == "" || strings.TrimSpace(sub.GroupID) == "" || strings.TrimSpace(sub.Endpoint) == "" {
+		log.Error("Missing required subscription fields")
+		handleBadRequest(w)
+		return
+	}
+
+	switch sub.Target {
+	case TargetHTTP, TargetFile, TargetKafka:
+	default:
+		log.WithField("target", sub.Target).Error("Invalid subscription target")
+		handleBadRequest(w)
+		return
+	}
+
+	sub.ID = fmt.Sprintf("%s-%s-%d", sub.Topic, sub.GroupID, time.Now().UnixNano())
+
+	subscriptionManager.Create(&sub)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&sub)
+}
+
+func getSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id := params["id"]
+
+	sub, ok := subscriptionManager.Get(id)
+	if !ok {
+		log.WithField("id", id).Error("Subscription not found")
+		handleError(w, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sub)
+}
+
+func deleteSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id := params["id"]
+
+	if !subscriptionManager.Delete(id) {
+		log.WithField("id", id).Error("Subscription not found")
+		handleError(w, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
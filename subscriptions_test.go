@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubscriptionSetStatusSurfacesLastError(t *testing.T) {
+	sub := &Subscription{ID: "sub-1", Topic: "widgets"}
+	sub.setStatus(StatusRunning, nil)
+
+	data, err := json.Marshal(sub)
+	if err != nil {
+		t.Fatalf("Marshal() while running: %v", err)
+	}
+	if got, want := string(data), `"status":"running"`; !strings.Contains(got, want) {
+		t.Fatalf("Marshal() = %s, want it to contain %s", got, want)
+	}
+
+	sub.setStatus(StatusStopped, errors.New("dial tcp: connection refused"))
+
+	var decoded struct {
+		Status    SubscriptionStatus `json:"status"`
+		LastError string             `json:"last_error"`
+	}
+	data, err = json.Marshal(sub)
+	if err != nil {
+		t.Fatalf("Marshal() after failure: %v", err)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal(): %v", err)
+	}
+
+	if decoded.Status != StatusStopped {
+		t.Errorf("Status = %q, want %q", decoded.Status, StatusStopped)
+	}
+	if decoded.LastError == "" {
+		t.Error("LastError = \"\", want the error that stopped the subscription")
+	}
+}
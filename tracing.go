@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mffonseca/golang-kafka-service-manager/config"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var tracer = otel.Tracer("golang-kafka-service-manager")
+
+// initTracing wires up the OpenTelemetry SDK for the backend named in the
+// tracing config block and registers it as the global tracer provider. An
+// empty Backend disables tracing and returns a no-op shutdown func.
+func initTracing(cfg config.TracingConfig) (func(context.Context) error, error) {
+	if cfg.Backend == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newTraceExporter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s trace exporter: %w", cfg.Backend, err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "golang-kafka-service-manager"
+	}
+
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio <= 0 {
+		sampleRatio = 1.0
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceNameKey.String(serviceName),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+func newTraceExporter(cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Backend {
+	case "jaeger":
+		return jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(cfg.Endpoint)))
+	case "zipkin":
+		return zipkin.New(cfg.Endpoint)
+	case "otlp":
+		return otlptracegrpc.New(context.Background(), otlptracegrpc.WithEndpoint(cfg.Endpoint), otlptracegrpc.WithInsecure())
+	default:
+		return nil, fmt.Errorf("unsupported tracing backend %q", cfg.Backend)
+	}
+}
+
+// kafkaHeaderCarrier adapts a *[]kafka.Header to OpenTelemetry's
+// TextMapCarrier so trace context can travel as a Kafka message header
+// (traceparent) and let downstream consumers continue the trace.
+type kafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// injectTraceContext writes the current span's context into the message
+// headers as a traceparent header.
+func injectTraceContext(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, kafkaHeaderCarrier{headers: &msg.Headers})
+}
+
+// extractTraceContext reads a traceparent header off a consumed message, if
+// present, so a consumer span can continue the producer's trace.
+func extractTraceContext(ctx context.Context, msg kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, kafkaHeaderCarrier{headers: &msg.Headers})
+}